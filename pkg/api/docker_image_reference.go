@@ -0,0 +1,97 @@
+package api
+
+import "strings"
+
+// DockerImageReference is a parsed pull spec for an image hosted on a
+// Docker registry, e.g. "quay.io/foo/bar:v1" or
+// "docker.io/library/busybox@sha256:abcd...". It mirrors the subset of
+// OpenShift's own reference parsing that ci-operator needs in order to
+// identify an external image and its default tag.
+type DockerImageReference struct {
+	Registry  string
+	Namespace string
+	Name      string
+	Tag       string
+	ID        string
+}
+
+// ParseDockerImageReference parses a Docker pull spec of the form
+// [[registry/]namespace/]name[:tag|@id] into its components. The
+// registry is only recognized when it contains a "." or ":" or is
+// "localhost", matching Docker's own disambiguation rule between a
+// registry host and an image namespace.
+func ParseDockerImageReference(spec string) (DockerImageReference, error) {
+	var ref DockerImageReference
+
+	name := spec
+	if at := strings.LastIndex(name, "@"); at != -1 {
+		ref.ID = name[at+1:]
+		name = name[:at]
+	} else if colon := strings.LastIndex(name, ":"); colon != -1 && !strings.Contains(name[colon+1:], "/") {
+		ref.Tag = name[colon+1:]
+		name = name[:colon]
+	}
+
+	parts := strings.Split(name, "/")
+	switch len(parts) {
+	case 1:
+		ref.Name = parts[0]
+	case 2:
+		if isRegistryComponent(parts[0]) {
+			ref.Registry = parts[0]
+			ref.Name = parts[1]
+		} else {
+			ref.Namespace = parts[0]
+			ref.Name = parts[1]
+		}
+	default:
+		if isRegistryComponent(parts[0]) {
+			ref.Registry = parts[0]
+			parts = parts[1:]
+		}
+		ref.Namespace = strings.Join(parts[:len(parts)-1], "/")
+		ref.Name = parts[len(parts)-1]
+	}
+
+	if len(ref.Name) == 0 {
+		return ref, errInvalidDockerImageReference(spec)
+	}
+	if len(ref.Tag) == 0 && len(ref.ID) == 0 {
+		ref.Tag = "latest"
+	}
+	return ref, nil
+}
+
+func isRegistryComponent(s string) bool {
+	return strings.ContainsAny(s, ".:") || s == "localhost"
+}
+
+type errInvalidDockerImageReference string
+
+func (e errInvalidDockerImageReference) Error() string {
+	return "invalid docker image reference: " + string(e)
+}
+
+// Exact reports whether the reference identifies an image by digest
+// rather than a mutable tag.
+func (r DockerImageReference) Exact() bool {
+	return len(r.ID) > 0
+}
+
+// String reassembles the reference into a pull spec.
+func (r DockerImageReference) String() string {
+	var out string
+	if len(r.Registry) > 0 {
+		out += r.Registry + "/"
+	}
+	if len(r.Namespace) > 0 {
+		out += r.Namespace + "/"
+	}
+	out += r.Name
+	if len(r.ID) > 0 {
+		out += "@" + r.ID
+	} else if len(r.Tag) > 0 {
+		out += ":" + r.Tag
+	}
+	return out
+}
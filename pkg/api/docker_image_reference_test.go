@@ -0,0 +1,80 @@
+package api
+
+import "testing"
+
+func TestParseDockerImageReference(t *testing.T) {
+	tests := []struct {
+		spec     string
+		expected DockerImageReference
+	}{
+		{
+			spec:     "busybox",
+			expected: DockerImageReference{Name: "busybox", Tag: "latest"},
+		},
+		{
+			spec:     "library/busybox:v1",
+			expected: DockerImageReference{Namespace: "library", Name: "busybox", Tag: "v1"},
+		},
+		{
+			spec:     "quay.io/foo/bar:v1",
+			expected: DockerImageReference{Registry: "quay.io", Namespace: "foo", Name: "bar", Tag: "v1"},
+		},
+		{
+			spec:     "localhost:5000/foo/bar",
+			expected: DockerImageReference{Registry: "localhost:5000", Namespace: "foo", Name: "bar", Tag: "latest"},
+		},
+		{
+			spec:     "registry.svc.ci.openshift.org/ocp/4.2:installer",
+			expected: DockerImageReference{Registry: "registry.svc.ci.openshift.org", Namespace: "ocp", Name: "4.2", Tag: "installer"},
+		},
+		{
+			spec:     "quay.io/foo/bar@sha256:abcd",
+			expected: DockerImageReference{Registry: "quay.io", Namespace: "foo", Name: "bar", ID: "sha256:abcd"},
+		},
+		{
+			spec:     "docker.io/library/busybox@sha256:abcd",
+			expected: DockerImageReference{Registry: "docker.io", Namespace: "library", Name: "busybox", ID: "sha256:abcd"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.spec, func(t *testing.T) {
+			ref, err := ParseDockerImageReference(test.spec)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if ref != test.expected {
+				t.Errorf("got %#v, expected %#v", ref, test.expected)
+			}
+		})
+	}
+}
+
+func TestParseDockerImageReferenceInvalid(t *testing.T) {
+	for _, spec := range []string{"", ":", "@"} {
+		if _, err := ParseDockerImageReference(spec); err == nil {
+			t.Errorf("expected an error parsing %q", spec)
+		}
+	}
+}
+
+func TestDockerImageReferenceExactAndString(t *testing.T) {
+	tagged, err := ParseDockerImageReference("quay.io/foo/bar:v1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if tagged.Exact() {
+		t.Errorf("a tag-based reference should not be Exact")
+	}
+	if tagged.String() != "quay.io/foo/bar:v1" {
+		t.Errorf("got %q", tagged.String())
+	}
+
+	tagged.Tag = ""
+	tagged.ID = "sha256:abcd"
+	if !tagged.Exact() {
+		t.Errorf("a digest-based reference should be Exact")
+	}
+	if tagged.String() != "quay.io/foo/bar@sha256:abcd" {
+		t.Errorf("got %q", tagged.String())
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"sync"
 )
 
 // Step is a self-contained bit of work that the
@@ -50,6 +51,31 @@ func (l *externalImageLink) Matches(other StepLink) bool {
 	}
 }
 
+// ExternalDockerImageLink returns a StepLink for an image hosted
+// outside of any OpenShift ImageStream ci-operator has access to.
+// Steps that import the same DockerImageReference (by registry,
+// namespace, name and tag or ID) share a single underlying import.
+func ExternalDockerImageLink(ref DockerImageReference) StepLink {
+	return &externalDockerImageLink{image: ref}
+}
+
+type externalDockerImageLink struct {
+	image DockerImageReference
+}
+
+func (l *externalDockerImageLink) Matches(other StepLink) bool {
+	switch link := other.(type) {
+	case *externalDockerImageLink:
+		return l.image.Registry == link.image.Registry &&
+			l.image.Namespace == link.image.Namespace &&
+			l.image.Name == link.image.Name &&
+			l.image.Tag == link.image.Tag &&
+			l.image.ID == link.image.ID
+	default:
+		return false
+	}
+}
+
 func InternalImageLink(ref PipelineImageStreamTagReference) StepLink {
 	return &internalImageLink{image: ref}
 }
@@ -208,6 +234,108 @@ func addToNode(parent, child *StepNode) bool {
 	return true
 }
 
+// StepHook is invoked around the execution of a single step. err is
+// nil in the OnStepStart call and carries the result of Step.Run in
+// the OnStepFinish call.
+type StepHook func(step Step, err error)
+
+// Execute runs the steps reachable from roots concurrently, honoring
+// the dependency order encoded in the graph: a step only starts once
+// every step that creates something it requires has finished. Up to
+// maxParallel steps run at once. The first error returned by any
+// step's Run cancels the context passed to every other in-flight and
+// not-yet-started step, and Execute returns that error once all
+// in-flight steps have unwound.
+//
+// Cancellation here is cooperative, not preemptive: it stops steps
+// that have not yet started and steps that check ctx.Err() between
+// their own blocking calls (as the steps in pkg/steps do), but
+// it cannot abort a Kubernetes API call already in flight, since the
+// typed clientset Getters the steps in pkg/steps are built against
+// (e.g. imageclientset.ImageStreamTagsGetter) do not accept a context
+// on Get/Create/List. Aborting an in-flight call would additionally
+// require wiring a context-aware RoundTripper into the REST config
+// used to build those clientsets, which is out of scope here.
+func Execute(ctx context.Context, roots []*StepNode, maxParallel int, onStart, onFinish StepHook) error {
+	if maxParallel <= 0 {
+		maxParallel = 1
+	}
+
+	childToParents := map[*StepNode]int{}
+	var collect func(node *StepNode, seen map[*StepNode]bool)
+	collect = func(node *StepNode, seen map[*StepNode]bool) {
+		if seen[node] {
+			return
+		}
+		seen[node] = true
+		for _, child := range node.Children {
+			childToParents[child]++
+			collect(child, seen)
+		}
+	}
+	seen := map[*StepNode]bool{}
+	for _, root := range roots {
+		collect(root, seen)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		mu       sync.Mutex
+		firstErr error
+		wg       sync.WaitGroup
+		sem      = make(chan struct{}, maxParallel)
+	)
+
+	var run func(node *StepNode)
+	run = func(node *StepNode) {
+		defer wg.Done()
+		sem <- struct{}{}
+		defer func() { <-sem }()
+
+		if ctx.Err() != nil {
+			return
+		}
+
+		if onStart != nil {
+			onStart(node.Step, nil)
+		}
+		err := node.Step.Run(ctx, false)
+		if onFinish != nil {
+			onFinish(node.Step, err)
+		}
+		if err != nil {
+			mu.Lock()
+			if firstErr == nil {
+				firstErr = err
+				cancel()
+			}
+			mu.Unlock()
+			return
+		}
+
+		for _, child := range node.Children {
+			mu.Lock()
+			childToParents[child]--
+			ready := childToParents[child] == 0
+			mu.Unlock()
+			if ready {
+				wg.Add(1)
+				go run(child)
+			}
+		}
+	}
+
+	for _, root := range roots {
+		wg.Add(1)
+		go run(root)
+	}
+	wg.Wait()
+
+	return firstErr
+}
+
 func HasAnyLinks(steps, candidates []StepLink) bool {
 	for _, candidate := range candidates {
 		for _, step := range steps {
@@ -0,0 +1,64 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+)
+
+type fakeStep struct {
+	name     string
+	requires []StepLink
+	creates  []StepLink
+	run      func(ctx context.Context) error
+}
+
+func (s *fakeStep) Inputs(ctx context.Context, dry bool) (InputDefinition, error) { return nil, nil }
+func (s *fakeStep) Run(ctx context.Context, dry bool) error                      { return s.run(ctx) }
+func (s *fakeStep) Done() (bool, error)                                          { return true, nil }
+func (s *fakeStep) Name() string                                                { return s.name }
+func (s *fakeStep) Requires() []StepLink                                        { return s.requires }
+func (s *fakeStep) Creates() []StepLink                                         { return s.creates }
+func (s *fakeStep) Provides() (ParameterMap, StepLink)                          { return nil, nil }
+
+func TestExecuteRunsInDependencyOrder(t *testing.T) {
+	linkA := ImagesReadyLink()
+	var mu sync.Mutex
+	var order []string
+	record := func(name string) func(ctx context.Context) error {
+		return func(ctx context.Context) error {
+			mu.Lock()
+			order = append(order, name)
+			mu.Unlock()
+			return nil
+		}
+	}
+
+	a := &fakeStep{name: "a", creates: []StepLink{linkA}, run: record("a")}
+	b := &fakeStep{name: "b", requires: []StepLink{linkA}, run: record("b")}
+
+	roots := BuildGraph([]Step{a, b})
+	if err := Execute(context.Background(), roots, 2, nil, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(order) != 2 || order[0] != "a" || order[1] != "b" {
+		t.Errorf("expected a to run before b, got %v", order)
+	}
+}
+
+func TestExecutePropagatesErrorAndCancels(t *testing.T) {
+	boom := fmt.Errorf("boom")
+	a := &fakeStep{name: "a", run: func(ctx context.Context) error { return boom }}
+	b := &fakeStep{name: "b", run: func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}}
+
+	roots := BuildGraph([]Step{a, b})
+	err := Execute(context.Background(), roots, 2, nil, nil)
+	if err != boom {
+		t.Fatalf("expected the first step's error to be returned, got %v", err)
+	}
+}
@@ -0,0 +1,59 @@
+package api
+
+// ImageStreamTagReference identifies an ImageStreamTag in an
+// OpenShift cluster, either the one ci-operator is running in
+// or a remote one that is reachable from it.
+type ImageStreamTagReference struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	Tag       string `json:"tag"`
+}
+
+// PipelineImageStreamTagReference is the name of a tag in the
+// ci-operator-managed pipeline ImageStream.
+type PipelineImageStreamTagReference string
+
+// InputImageTagStepConfiguration describes a step that tags an
+// externally-built image into the pipeline ImageStream so that
+// later steps in the job can build on top of it.
+type InputImageTagStepConfiguration struct {
+	// BaseImage identifies the ImageStreamTag to tag in. It is
+	// ignored if DockerImage is set.
+	BaseImage ImageStreamTagReference `json:"base_image,omitempty"`
+	// DockerImage is a pull spec for an image hosted on a Docker
+	// registry (e.g. "quay.io/foo/bar:v1"), used instead of
+	// BaseImage when the source image does not live in an
+	// ImageStream reachable by ci-operator. When set, ci-operator
+	// imports it into the job's namespace via an
+	// ImageStreamImport before tagging it into the pipeline.
+	DockerImage string `json:"docker_image,omitempty"`
+	// InsecureRegistry allows the import of DockerImage to pull
+	// from a registry without a valid TLS certificate or over
+	// plain HTTP.
+	InsecureRegistry bool `json:"insecure_registry,omitempty"`
+	// Scheduled requests that the cluster periodically re-import
+	// DockerImage on its own import cadence, so long-running jobs
+	// see upstream content refresh without manual intervention.
+	Scheduled bool `json:"scheduled,omitempty"`
+	// PinDigest resolves DockerImage to a digest on first import
+	// and pins all later resolutions to that digest, so repeated
+	// runs are reproducible even if the upstream tag moves.
+	PinDigest bool `json:"pin_digest,omitempty"`
+	// To is the tag that will be created in the pipeline
+	// ImageStream.
+	To PipelineImageStreamTagReference `json:"to"`
+}
+
+// ReleaseTagConfiguration determines how the release images are
+// tagged in from another imagestream, and whether they are held
+// stable or allowed to follow the upstream as it changes.
+type ReleaseTagConfiguration struct {
+	Namespace string `json:"namespace"`
+
+	Name string `json:"name,omitempty"`
+	Tag  string `json:"tag,omitempty"`
+
+	NamePrefix string `json:"name_prefix,omitempty"`
+
+	TagOverrides map[string]string `json:"tag_overrides,omitempty"`
+}
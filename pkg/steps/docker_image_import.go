@@ -0,0 +1,137 @@
+package steps
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	imageapi "github.com/openshift/api/image/v1"
+	"github.com/openshift/ci-operator/pkg/api"
+	imageclientset "github.com/openshift/client-go/image/clientset/versioned/typed/image/v1"
+	coreapi "k8s.io/api/core/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+// ExternalImageImport holds the result of resolving a DockerImageReference
+// against the cluster. Every inputImageTagStep that tags the same external
+// image is constructed with a pointer to the same ExternalImageImport, so
+// they all read the one digest a single dockerImageImportStep resolved
+// rather than each importing the reference for themselves.
+type ExternalImageImport struct {
+	Ref    api.DockerImageReference
+	Digest string
+}
+
+// dockerImageImportStep creates a single ImageStreamImport that resolves a
+// DockerImageReference to a digest and records it in result, so every
+// inputImageTagStep sharing that pointer can tag from it without each
+// importing the same external image on its own.
+type dockerImageImportStep struct {
+	ref          api.DockerImageReference
+	insecure     bool
+	scheduled    bool
+	pinDigest    bool
+	importClient imageclientset.ImageStreamImportsGetter
+	jobSpec      *JobSpec
+	result       *ExternalImageImport
+}
+
+func (s *dockerImageImportStep) Inputs(ctx context.Context, dry bool) (api.InputDefinition, error) {
+	return nil, nil
+}
+
+func (s *dockerImageImportStep) Run(ctx context.Context, dry bool) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	if dry {
+		s.result.Digest = "dry-fake-digest"
+		return nil
+	}
+
+	isi := &imageapi.ImageStreamImport{
+		ObjectMeta: meta.ObjectMeta{
+			Name:      fmt.Sprintf("%s-import-%s", PipelineImageStream, s.ref.Name),
+			Namespace: s.jobSpec.Namespace(),
+		},
+		Spec: imageapi.ImageStreamImportSpec{
+			Import: true,
+			Images: []imageapi.ImageImportSpec{{
+				From: coreapi.ObjectReference{
+					Kind: "DockerImage",
+					Name: s.ref.String(),
+				},
+				ImportPolicy: imageapi.TagImportPolicy{
+					Insecure:  s.insecure,
+					Scheduled: s.scheduled,
+				},
+			}},
+		},
+	}
+
+	timeoutCtx, cancel := context.WithTimeout(ctx, importPollTimeout)
+	defer cancel()
+
+	var digest string
+	err := wait.PollImmediateUntil(importPollInterval, func() (bool, error) {
+		imported, err := s.importClient.ImageStreamImports(s.jobSpec.Namespace()).Create(isi)
+		if err != nil {
+			return false, fmt.Errorf("could not import %s: %v", s.ref.String(), err)
+		}
+		if len(imported.Status.Images) == 0 || imported.Status.Images[0].Image == nil {
+			return false, nil
+		}
+		digest = imported.Status.Images[0].Image.Name
+		return true, nil
+	}, timeoutCtx.Done())
+	if err != nil {
+		return fmt.Errorf("failed waiting for import of %s to complete: %v", s.ref.String(), err)
+	}
+	log.Printf("Resolved %s to %s", s.ref.String(), digest)
+
+	resolved := s.ref
+	if s.pinDigest && !resolved.Exact() {
+		resolved.Tag = ""
+		resolved.ID = digest
+		log.Printf("Pinned docker_image to %s", resolved.String())
+	}
+	s.result.Ref = resolved
+	s.result.Digest = digest
+	return nil
+}
+
+func (s *dockerImageImportStep) Done() (bool, error) {
+	return len(s.result.Digest) > 0, nil
+}
+
+func (s *dockerImageImportStep) Requires() []api.StepLink { return nil }
+
+func (s *dockerImageImportStep) Creates() []api.StepLink {
+	return []api.StepLink{api.ExternalDockerImageLink(s.ref)}
+}
+
+func (s *dockerImageImportStep) Provides() (api.ParameterMap, api.StepLink) { return nil, nil }
+
+func (s *dockerImageImportStep) Name() string { return "" }
+
+// NewDockerImageImportStep creates a step that performs a single
+// ImageStreamImport for ref and records the digest it resolves to in the
+// returned ExternalImageImport. Construct one of these per unique
+// DockerImageReference and pass the same ExternalImageImport pointer to
+// every InputImageTagStep that tags that reference, so the graph built by
+// api.BuildGraph schedules the import once ahead of every dependent tag
+// step instead of having each tag step import the reference itself.
+func NewDockerImageImportStep(ref api.DockerImageReference, insecure, scheduled, pinDigest bool, importClient imageclientset.ImageStreamImportsGetter, jobSpec *JobSpec) (api.Step, *ExternalImageImport) {
+	result := &ExternalImageImport{Ref: ref}
+	return &dockerImageImportStep{
+		ref:          ref,
+		insecure:     insecure,
+		scheduled:    scheduled,
+		pinDigest:    pinDigest,
+		importClient: importClient,
+		jobSpec:      jobSpec,
+		result:       result,
+	}, result
+}
@@ -0,0 +1,50 @@
+package steps
+
+import (
+	"context"
+	"testing"
+
+	"github.com/openshift/ci-operator/pkg/api"
+)
+
+func TestDockerImageImportStepRunDry(t *testing.T) {
+	ref, err := api.ParseDockerImageReference("quay.io/foo/bar:v1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	step, result := NewDockerImageImportStep(ref, false, false, false, nil, &JobSpec{})
+
+	if err := step.Run(context.Background(), true); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Digest != "dry-fake-digest" {
+		t.Errorf("got %q, expected dry-fake-digest", result.Digest)
+	}
+}
+
+func TestDockerImageImportStepCreatesSharedLink(t *testing.T) {
+	ref, err := api.ParseDockerImageReference("quay.io/foo/bar:v1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	importStep, result := NewDockerImageImportStep(ref, false, false, false, nil, &JobSpec{})
+
+	tagA := &inputImageTagStep{
+		config:         api.InputImageTagStepConfiguration{DockerImage: "quay.io/foo/bar:v1", To: "a"},
+		jobSpec:        &JobSpec{},
+		externalImport: result,
+	}
+	tagB := &inputImageTagStep{
+		config:         api.InputImageTagStepConfiguration{DockerImage: "quay.io/foo/bar:v1", To: "b"},
+		jobSpec:        &JobSpec{},
+		externalImport: result,
+	}
+
+	roots := api.BuildGraph([]api.Step{importStep, tagA, tagB})
+	if len(roots) != 1 {
+		t.Fatalf("expected a single root step, got %d", len(roots))
+	}
+	if len(roots[0].Children) != 2 {
+		t.Fatalf("expected the import step to have 2 dependents, got %d", len(roots[0].Children))
+	}
+}
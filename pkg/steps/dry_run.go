@@ -0,0 +1,180 @@
+package steps
+
+import (
+	"encoding/json"
+	"fmt"
+
+	imageapi "github.com/openshift/api/image/v1"
+	coreapi "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/yaml"
+)
+
+// DryRunSink collects the objects a step would have created while
+// running in dry mode and is responsible for presenting them to the
+// user once the step is done adding to it. Add may be called more
+// than once per step invocation; Flush emits whatever has been
+// buffered and resets the sink for the next step.
+type DryRunSink interface {
+	Add(obj runtime.Object) error
+	Flush() error
+}
+
+// stdoutDryRunSink prints each object as its own JSON document. This
+// is ci-operator's historical dry-run behavior and remains the
+// default when a step is not given another sink.
+type stdoutDryRunSink struct{}
+
+// NewStdoutDryRunSink returns the default DryRunSink.
+func NewStdoutDryRunSink() DryRunSink {
+	return stdoutDryRunSink{}
+}
+
+func (stdoutDryRunSink) Add(obj runtime.Object) error {
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return fmt.Errorf("failed to marshal object: %v", err)
+	}
+	fmt.Printf("%s\n", data)
+	return nil
+}
+
+func (stdoutDryRunSink) Flush() error { return nil }
+
+// listDryRunSink buffers every object added to it and emits them
+// together as a single v1.List, so dry-run output can be diffed as
+// one manifest. Items are written as the steps built them: the sink
+// does not fill in an item's kind or apiVersion.
+type listDryRunSink struct {
+	list coreapi.List
+}
+
+// NewListDryRunSink returns a DryRunSink that coalesces everything
+// added to it into one v1.List.
+func NewListDryRunSink() DryRunSink {
+	return &listDryRunSink{}
+}
+
+func (s *listDryRunSink) Add(obj runtime.Object) error {
+	s.list.Items = append(s.list.Items, runtime.RawExtension{Object: obj})
+	return nil
+}
+
+func (s *listDryRunSink) Flush() error {
+	s.list.Kind = "List"
+	s.list.APIVersion = "v1"
+	data, err := json.MarshalIndent(&s.list, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal manifest list: %v", err)
+	}
+	fmt.Printf("%s\n", data)
+	s.list = coreapi.List{}
+	return nil
+}
+
+// yamlDryRunSink buffers every object added to it and, on Flush, emits
+// them as a YAML multi-document stream (each document separated by
+// "---"), so dry-run output can be diffed as one manifest. Objects
+// are written as the steps built them: the sink does not fill in
+// their kind or apiVersion.
+type yamlDryRunSink struct {
+	objects []runtime.Object
+}
+
+// NewYAMLDryRunSink returns a DryRunSink that emits everything added to
+// it as a single YAML multi-document stream.
+func NewYAMLDryRunSink() DryRunSink {
+	return &yamlDryRunSink{}
+}
+
+func (s *yamlDryRunSink) Add(obj runtime.Object) error {
+	s.objects = append(s.objects, obj)
+	return nil
+}
+
+func (s *yamlDryRunSink) Flush() error {
+	for i, obj := range s.objects {
+		data, err := yaml.Marshal(obj)
+		if err != nil {
+			return fmt.Errorf("failed to marshal object as yaml: %v", err)
+		}
+		if i > 0 {
+			fmt.Printf("---\n")
+		}
+		fmt.Printf("%s", data)
+	}
+	s.objects = nil
+	return nil
+}
+
+// imageStreamImportDryRunSink coalesces the tags of a single source
+// ImageStream into one ImageStreamImport carrying one Images[] entry
+// per tag, mirroring how the OpenShift import controller batches tag
+// imports for a stream. It accepts either an *imageapi.ImageStream
+// (each of whose Spec.Tags with a From is expanded; tags without a
+// From are skipped) or individual
+// *imageapi.ImageStreamTag objects, but not a mix of tags from more
+// than one named stream in between calls to Flush: call Flush once a
+// stream's tags have all been added to start collecting the next
+// stream's tags.
+type imageStreamImportDryRunSink struct {
+	namespace string
+	isi       imageapi.ImageStreamImport
+}
+
+// NewImageStreamImportDryRunSink returns a DryRunSink that coalesces
+// the tags of one source ImageStream at a time into a single
+// ImageStreamImport for the given namespace.
+func NewImageStreamImportDryRunSink(namespace string) DryRunSink {
+	return &imageStreamImportDryRunSink{namespace: namespace}
+}
+
+func (s *imageStreamImportDryRunSink) Add(obj runtime.Object) error {
+	switch o := obj.(type) {
+	case *imageapi.ImageStream:
+		for _, tag := range o.Spec.Tags {
+			if tag.From == nil {
+				continue
+			}
+			if err := s.addTag(o.Name, tag.Name, *tag.From); err != nil {
+				return err
+			}
+		}
+		return nil
+	case *imageapi.ImageStreamTag:
+		if o.Tag == nil || o.Tag.From == nil {
+			return fmt.Errorf("imagestreamtag %s has no source to import", o.Name)
+		}
+		name, tag, ok := SplitImageStreamTag(o.Name)
+		if !ok {
+			return fmt.Errorf("imagestreamtag name %q is not of the form name:tag", o.Name)
+		}
+		return s.addTag(name, tag, *o.Tag.From)
+	default:
+		return fmt.Errorf("this sink only accepts ImageStream or ImageStreamTag objects, got %T", obj)
+	}
+}
+
+func (s *imageStreamImportDryRunSink) addTag(streamName, tag string, from coreapi.ObjectReference) error {
+	if len(s.isi.Name) > 0 && s.isi.Name != streamName {
+		return fmt.Errorf("this sink is already collecting imports for image stream %q, got a tag for %q — call Flush first", s.isi.Name, streamName)
+	}
+	s.isi.Name = streamName
+	s.isi.Namespace = s.namespace
+	s.isi.Spec.Import = true
+	s.isi.Spec.Images = append(s.isi.Spec.Images, imageapi.ImageImportSpec{
+		From: from,
+		To:   &coreapi.LocalObjectReference{Name: tag},
+	})
+	return nil
+}
+
+func (s *imageStreamImportDryRunSink) Flush() error {
+	data, err := json.Marshal(&s.isi)
+	if err != nil {
+		return fmt.Errorf("failed to marshal imagestreamimport: %v", err)
+	}
+	fmt.Printf("%s\n", data)
+	s.isi = imageapi.ImageStreamImport{}
+	return nil
+}
@@ -0,0 +1,112 @@
+package steps
+
+import (
+	"testing"
+
+	imageapi "github.com/openshift/api/image/v1"
+	coreapi "k8s.io/api/core/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestImageStreamImportDryRunSinkCoalesces(t *testing.T) {
+	sink := NewImageStreamImportDryRunSink("ci-op-123").(*imageStreamImportDryRunSink)
+
+	for _, tag := range []string{"installer", "cli"} {
+		ist := &imageapi.ImageStreamTag{
+			ObjectMeta: meta.ObjectMeta{Name: "stable:" + tag},
+			Tag: &imageapi.TagReference{
+				From: &coreapi.ObjectReference{Kind: "ImageStreamImage", Name: "stable@sha256:abc"},
+			},
+		}
+		if err := sink.Add(ist); err != nil {
+			t.Fatalf("unexpected error adding %s: %v", tag, err)
+		}
+	}
+
+	if sink.isi.Name != "stable" || sink.isi.Namespace != "ci-op-123" {
+		t.Errorf("unexpected import target: %s/%s", sink.isi.Namespace, sink.isi.Name)
+	}
+	if len(sink.isi.Spec.Images) != 2 {
+		t.Fatalf("expected 2 coalesced images, got %d", len(sink.isi.Spec.Images))
+	}
+}
+
+func TestImageStreamImportDryRunSinkExpandsImageStream(t *testing.T) {
+	sink := NewImageStreamImportDryRunSink("ci-op-123").(*imageStreamImportDryRunSink)
+	is := &imageapi.ImageStream{
+		ObjectMeta: meta.ObjectMeta{Name: "centos"},
+		Spec: imageapi.ImageStreamSpec{
+			Tags: []imageapi.TagReference{
+				{Name: "7", From: &coreapi.ObjectReference{Kind: "DockerImage", Name: "centos@sha256:abc"}},
+				{Name: "8", From: &coreapi.ObjectReference{Kind: "DockerImage", Name: "centos@sha256:def"}},
+			},
+		},
+	}
+	if err := sink.Add(is); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if sink.isi.Name != "centos" || len(sink.isi.Spec.Images) != 2 {
+		t.Errorf("expected both tags of centos to be coalesced, got %#v", sink.isi)
+	}
+}
+
+func TestImageStreamImportDryRunSinkRejectsOtherTypes(t *testing.T) {
+	sink := NewImageStreamImportDryRunSink("ci-op-123")
+	if err := sink.Add(&coreapi.ConfigMap{}); err == nil {
+		t.Errorf("expected an error for an unsupported object type")
+	}
+}
+
+func TestImageStreamImportDryRunSinkRejectsMixedStreams(t *testing.T) {
+	sink := NewImageStreamImportDryRunSink("ci-op-123")
+	centos := &imageapi.ImageStreamTag{
+		ObjectMeta: meta.ObjectMeta{Name: "centos:7"},
+		Tag:        &imageapi.TagReference{From: &coreapi.ObjectReference{Kind: "DockerImage", Name: "centos@sha256:abc"}},
+	}
+	rhel := &imageapi.ImageStreamTag{
+		ObjectMeta: meta.ObjectMeta{Name: "rhel:8"},
+		Tag:        &imageapi.TagReference{From: &coreapi.ObjectReference{Kind: "DockerImage", Name: "rhel@sha256:def"}},
+	}
+	if err := sink.Add(centos); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := sink.Add(rhel); err == nil {
+		t.Errorf("expected an error when mixing tags from two different image streams without a Flush in between")
+	}
+}
+
+func TestYAMLDryRunSinkFlushResetsBuffer(t *testing.T) {
+	sink := NewYAMLDryRunSink().(*yamlDryRunSink)
+	ist := &imageapi.ImageStreamTag{ObjectMeta: meta.ObjectMeta{Name: "stable:cli"}}
+	if err := sink.Add(ist); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(sink.objects) != 1 {
+		t.Fatalf("expected 1 buffered object, got %d", len(sink.objects))
+	}
+
+	if err := sink.Flush(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(sink.objects) != 0 {
+		t.Errorf("expected Flush to reset the buffered objects")
+	}
+}
+
+func TestListDryRunSinkFlushProducesAList(t *testing.T) {
+	sink := NewListDryRunSink().(*listDryRunSink)
+	ist := &imageapi.ImageStreamTag{ObjectMeta: meta.ObjectMeta{Name: "stable:cli"}}
+	if err := sink.Add(ist); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(sink.list.Items) != 1 {
+		t.Fatalf("expected 1 buffered item, got %d", len(sink.list.Items))
+	}
+
+	if err := sink.Flush(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(sink.list.Items) != 0 {
+		t.Errorf("expected Flush to reset the buffered list")
+	}
+}
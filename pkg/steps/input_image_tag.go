@@ -2,9 +2,9 @@ package steps
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"log"
+	"time"
 
 	imageapi "github.com/openshift/api/image/v1"
 	"github.com/openshift/ci-operator/pkg/api"
@@ -14,23 +14,71 @@ import (
 	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
+const (
+	importPollInterval = 2 * time.Second
+	importPollTimeout  = 2 * time.Minute
+)
+
 // inputImageTagStep will ensure that a tag exists
 // in the pipeline ImageStream that resolves to
 // the base image
 type inputImageTagStep struct {
-	config  api.InputImageTagStepConfiguration
-	client  imageclientset.ImageStreamTagsGetter
-	jobSpec *JobSpec
+	config   api.InputImageTagStepConfiguration
+	client   imageclientset.ImageStreamTagsGetter
+	isClient imageclientset.ImageStreamsGetter
+	jobSpec  *JobSpec
+	sink     DryRunSink
+
+	// externalImport is shared with the dockerImageImportStep that
+	// resolves config.DockerImage, when set. It is nil for steps that
+	// resolve an in-cluster BaseImage instead.
+	externalImport *ExternalImageImport
 
 	imageName string
 }
 
+// isExternalImage reports whether the base image should be resolved
+// from an external Docker registry via DockerImage rather than an
+// in-cluster ImageStreamTag.
+func (s *inputImageTagStep) isExternalImage() bool {
+	return len(s.config.DockerImage) > 0
+}
+
 func (s *inputImageTagStep) Inputs(ctx context.Context, dry bool) (api.InputDefinition, error) {
 	if len(s.imageName) > 0 {
 		return api.InputDefinition{s.imageName}, nil
 	}
 
-	from, err := s.client.ImageStreamTags(s.config.BaseImage.Namespace).Get(fmt.Sprintf("%s:%s", s.config.BaseImage.Name, s.config.BaseImage.Tag), meta.GetOptions{})
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	if s.isExternalImage() {
+		if dry {
+			s.imageName = "dry-fake-digest"
+			return api.InputDefinition{s.imageName}, nil
+		}
+		if s.externalImport == nil || len(s.externalImport.Digest) == 0 {
+			return nil, fmt.Errorf("base image %s has not been imported yet", s.config.DockerImage)
+		}
+		s.imageName = s.externalImport.Digest
+		return api.InputDefinition{s.imageName}, nil
+	}
+
+	tag := s.config.BaseImage.Tag
+	if s.isClient != nil {
+		is, err := s.isClient.ImageStreams(s.config.BaseImage.Namespace).Get(s.config.BaseImage.Name, meta.GetOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("could not resolve base image: %v", err)
+		}
+		resolved, _, err := FollowTagReference(is, tag)
+		if err != nil {
+			return nil, fmt.Errorf("could not resolve base image: %v", err)
+		}
+		tag = resolved
+	}
+
+	from, err := s.client.ImageStreamTags(s.config.BaseImage.Namespace).Get(fmt.Sprintf("%s:%s", s.config.BaseImage.Name, tag), meta.GetOptions{})
 	if err != nil {
 		return nil, fmt.Errorf("could not resolve base image: %v", err)
 	}
@@ -39,15 +87,32 @@ func (s *inputImageTagStep) Inputs(ctx context.Context, dry bool) (api.InputDefi
 	return api.InputDefinition{from.Image.Name}, nil
 }
 
-func (s *inputImageTagStep) Run(ctx context.Context, dry bool) error {
-	log.Printf("Tagging %s/%s:%s into %s:%s", s.config.BaseImage.Namespace, s.config.BaseImage.Name, s.config.BaseImage.Tag, PipelineImageStream, s.config.To)
-
-	_, err := s.Inputs(ctx, dry)
-	if err != nil {
-		return err
+// imageStreamTag builds the pipeline ImageStreamTag that points at
+// the resolved base image, whether it came from an in-cluster
+// ImageStreamTag or an imported external image.
+func (s *inputImageTagStep) imageStreamTag() *imageapi.ImageStreamTag {
+	from := &coreapi.ObjectReference{
+		Kind:      "ImageStreamImage",
+		Name:      fmt.Sprintf("%s@%s", s.config.BaseImage.Name, s.imageName),
+		Namespace: s.config.BaseImage.Namespace,
 	}
-
-	ist := &imageapi.ImageStreamTag{
+	if s.isExternalImage() {
+		name := fmt.Sprintf("%s@%s", s.config.DockerImage, s.imageName)
+		if ref, err := api.ParseDockerImageReference(s.config.DockerImage); err == nil {
+			// Rebuild from the parsed reference rather than
+			// appending to DockerImage directly: when DockerImage
+			// is already a "name@sha256:..." reference, string
+			// concatenation would double up the digest.
+			ref.Tag = ""
+			ref.ID = s.imageName
+			name = ref.String()
+		}
+		from = &coreapi.ObjectReference{
+			Kind: "DockerImage",
+			Name: name,
+		}
+	}
+	return &imageapi.ImageStreamTag{
 		ObjectMeta: meta.ObjectMeta{
 			Name:      fmt.Sprintf("%s:%s", PipelineImageStream, s.config.To),
 			Namespace: s.jobSpec.Namespace(),
@@ -56,20 +121,37 @@ func (s *inputImageTagStep) Run(ctx context.Context, dry bool) error {
 			ReferencePolicy: imageapi.TagReferencePolicy{
 				Type: imageapi.LocalTagReferencePolicy,
 			},
-			From: &coreapi.ObjectReference{
-				Kind:      "ImageStreamImage",
-				Name:      fmt.Sprintf("%s@%s", s.config.BaseImage.Name, s.imageName),
-				Namespace: s.config.BaseImage.Namespace,
+			From: from,
+			ImportPolicy: imageapi.TagImportPolicy{
+				Scheduled: s.config.Scheduled,
+				Insecure:  s.config.InsecureRegistry,
 			},
 		},
 	}
+}
+
+func (s *inputImageTagStep) Run(ctx context.Context, dry bool) error {
+	if s.isExternalImage() {
+		log.Printf("Tagging %s into %s:%s", s.config.DockerImage, PipelineImageStream, s.config.To)
+	} else {
+		log.Printf("Tagging %s/%s:%s into %s:%s", s.config.BaseImage.Namespace, s.config.BaseImage.Name, s.config.BaseImage.Tag, PipelineImageStream, s.config.To)
+	}
+
+	_, err := s.Inputs(ctx, dry)
+	if err != nil {
+		return err
+	}
+
+	ist := s.imageStreamTag()
 	if dry {
-		istJSON, err := json.Marshal(ist)
-		if err != nil {
-			return fmt.Errorf("failed to marshal imagestreamtag: %v", err)
+		if err := s.sink.Add(ist); err != nil {
+			return err
 		}
-		fmt.Printf("%s\n", istJSON)
-		return nil
+		return s.sink.Flush()
+	}
+
+	if ctx.Err() != nil {
+		return ctx.Err()
 	}
 
 	if _, err := s.client.ImageStreamTags(s.jobSpec.Namespace()).Create(ist); err != nil && !errors.IsAlreadyExists(err) {
@@ -96,6 +178,9 @@ func (s *inputImageTagStep) Done() (bool, error) {
 }
 
 func (s *inputImageTagStep) Requires() []api.StepLink {
+	if s.isExternalImage() && s.externalImport != nil {
+		return []api.StepLink{api.ExternalDockerImageLink(s.externalImport.Ref)}
+	}
 	return []api.StepLink{api.ExternalImageLink(s.config.BaseImage)}
 }
 
@@ -104,15 +189,39 @@ func (s *inputImageTagStep) Creates() []api.StepLink {
 }
 
 func (s *inputImageTagStep) Provides() (api.ParameterMap, api.StepLink) {
-	return nil, nil
+	if !s.isExternalImage() {
+		return nil, nil
+	}
+	return api.ParameterMap{
+		fmt.Sprintf("BASE_IMAGE_%s_DIGEST", componentToParamName(string(s.config.To))): func() (string, error) {
+			if len(s.imageName) == 0 {
+				return "", fmt.Errorf("base image %s has not been resolved yet", s.config.To)
+			}
+			return s.imageName, nil
+		},
+	}, api.InternalImageLink(s.config.To)
 }
 
 func (s *inputImageTagStep) Name() string { return "" }
 
-func InputImageTagStep(config api.InputImageTagStepConfiguration, client imageclientset.ImageStreamTagsGetter, jobSpec *JobSpec) api.Step {
+// InputImageTagStep creates a step that tags a single base image into
+// the pipeline ImageStream. sink, if nil, defaults to printing each
+// dry-run object to stdout as its own JSON document. externalImport
+// must be the result of NewDockerImageImportStep when config.DockerImage
+// is set, and must be shared across every InputImageTagStep that tags
+// the same external image so they wait on and reuse one import instead
+// of each importing it themselves; it is ignored when config.DockerImage
+// is empty.
+func InputImageTagStep(config api.InputImageTagStepConfiguration, client imageclientset.ImageStreamTagsGetter, isClient imageclientset.ImageStreamsGetter, externalImport *ExternalImageImport, jobSpec *JobSpec, sink DryRunSink) api.Step {
+	if sink == nil {
+		sink = NewStdoutDryRunSink()
+	}
 	return &inputImageTagStep{
-		config:  config,
-		client:  client,
-		jobSpec: jobSpec,
+		config:         config,
+		client:         client,
+		isClient:       isClient,
+		externalImport: externalImport,
+		jobSpec:        jobSpec,
+		sink:           sink,
 	}
 }
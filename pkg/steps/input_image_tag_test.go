@@ -0,0 +1,111 @@
+package steps
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	imageapi "github.com/openshift/api/image/v1"
+	"github.com/openshift/ci-operator/pkg/api"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// recordingSink is a DryRunSink that only records what it was given,
+// for assertions in tests.
+type recordingSink struct {
+	added []runtime.Object
+}
+
+func (s *recordingSink) Add(obj runtime.Object) error {
+	s.added = append(s.added, obj)
+	return nil
+}
+
+func (s *recordingSink) Flush() error { return nil }
+
+func TestImageStreamTagDoesNotDoubleAppendDigestWhenPinned(t *testing.T) {
+	s := &inputImageTagStep{
+		config: api.InputImageTagStepConfiguration{
+			// DockerImage is already an exact, digest-based
+			// reference.
+			DockerImage: "quay.io/foo/bar@sha256:abcd",
+			PinDigest:   true,
+			To:          "base",
+		},
+		jobSpec:   &JobSpec{},
+		imageName: "sha256:abcd",
+	}
+
+	ist := s.imageStreamTag()
+	name := ist.Tag.From.Name
+	if strings.Count(name, "@") != 1 {
+		t.Fatalf("expected exactly one digest separator in %q, got %d", name, strings.Count(name, "@"))
+	}
+	if name != "quay.io/foo/bar@sha256:abcd" {
+		t.Errorf("got %q, expected quay.io/foo/bar@sha256:abcd", name)
+	}
+}
+
+func TestImageStreamTagAppendsDigestOnce(t *testing.T) {
+	s := &inputImageTagStep{
+		config: api.InputImageTagStepConfiguration{
+			DockerImage: "quay.io/foo/bar:v1",
+			To:          "base",
+		},
+		jobSpec:   &JobSpec{},
+		imageName: "sha256:abcd",
+	}
+
+	ist := s.imageStreamTag()
+	name := ist.Tag.From.Name
+	if name != "quay.io/foo/bar@sha256:abcd" {
+		t.Errorf("got %q, expected quay.io/foo/bar@sha256:abcd", name)
+	}
+}
+
+func TestImageStreamTagSetsImportPolicy(t *testing.T) {
+	s := &inputImageTagStep{
+		config: api.InputImageTagStepConfiguration{
+			DockerImage:      "quay.io/foo/bar:v1",
+			To:               "base",
+			Scheduled:        true,
+			InsecureRegistry: true,
+		},
+		jobSpec:   &JobSpec{},
+		imageName: "sha256:abcd",
+	}
+
+	ist := s.imageStreamTag()
+	if !ist.Tag.ImportPolicy.Scheduled {
+		t.Errorf("expected ImportPolicy.Scheduled to be true")
+	}
+	if !ist.Tag.ImportPolicy.Insecure {
+		t.Errorf("expected ImportPolicy.Insecure to be true")
+	}
+}
+
+func TestInputImageTagStepRunDryExternalImage(t *testing.T) {
+	sink := &recordingSink{}
+	s := &inputImageTagStep{
+		config: api.InputImageTagStepConfiguration{
+			DockerImage: "quay.io/foo/bar:v1",
+			To:          "base",
+		},
+		jobSpec: &JobSpec{},
+		sink:    sink,
+	}
+
+	if err := s.Run(context.Background(), true); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(sink.added) != 1 {
+		t.Fatalf("expected 1 object added to the sink, got %d", len(sink.added))
+	}
+	ist, ok := sink.added[0].(*imageapi.ImageStreamTag)
+	if !ok {
+		t.Fatalf("expected an ImageStreamTag, got %T", sink.added[0])
+	}
+	if strings.Count(ist.Tag.From.Name, "@") != 1 {
+		t.Errorf("expected exactly one digest separator, got %q", ist.Tag.From.Name)
+	}
+}
@@ -35,6 +35,7 @@ type releaseImagesTagStep struct {
 	configMapClient coreclientset.ConfigMapsGetter
 	params          *DeferredParameters
 	jobSpec         *JobSpec
+	sink            DryRunSink
 }
 
 func findStatusTag(is *imageapi.ImageStream, tag string) *coreapi.ObjectReference {
@@ -72,6 +73,10 @@ func sourceName(config api.ReleaseTagConfiguration) string {
 }
 
 func (s *releaseImagesTagStep) Run(ctx context.Context, dry bool) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
 	log.Printf("Tagging release images from %s", sourceName(s.config))
 
 	if len(s.config.Name) > 0 {
@@ -85,22 +90,29 @@ func (s *releaseImagesTagStep) Run(ctx context.Context, dry bool) error {
 				Name: StableImageStream,
 			},
 		}
+		var unresolved []string
 		for _, tag := range is.Spec.Tags {
-			if valid := findStatusTag(is, tag.Name); valid != nil {
+			resolved, _, err := FollowTagReference(is, tag.Name)
+			if err != nil {
+				unresolved = append(unresolved, fmt.Sprintf("%s: %v", tag.Name, err))
+				continue
+			}
+			if valid := findStatusTag(is, resolved); valid != nil {
 				newIS.Spec.Tags = append(newIS.Spec.Tags, imageapi.TagReference{
 					Name: tag.Name,
 					From: valid,
 				})
 			}
 		}
+		if len(unresolved) > 0 {
+			return fmt.Errorf("could not resolve tags of stable imagestream %s/%s: %s", is.Namespace, is.Name, strings.Join(unresolved, "; "))
+		}
 
 		if dry {
-			istJSON, err := json.Marshal(newIS)
-			if err != nil {
-				return fmt.Errorf("failed to marshal image stream: %v", err)
+			if err := s.sink.Add(newIS); err != nil {
+				return err
 			}
-			fmt.Printf("%s\n", istJSON)
-			return nil
+			return s.sink.Flush()
 		}
 		is, err = s.isGetter.ImageStreams(s.jobSpec.Namespace()).Create(newIS)
 		if err != nil && !errors.IsAlreadyExists(err) {
@@ -124,23 +136,33 @@ func (s *releaseImagesTagStep) Run(ctx context.Context, dry bool) error {
 	}
 
 	for _, stableImageStream := range stableImageStreams.Items {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
 		log.Printf("Considering stable image stream %s", stableImageStream.Name)
 		targetTag := s.config.Tag
 		if override, ok := s.config.TagOverrides[stableImageStream.Name]; ok {
 			targetTag = override
 		}
 
+		addedTag := false
 		for _, tag := range stableImageStream.Spec.Tags {
 			if tag.Name == targetTag {
-				log.Printf("Cross-tagging %s:%s from %s/%s:%s", stableImageStream.Name, targetTag, stableImageStream.Namespace, stableImageStream.Name, targetTag)
+				resolvedTag, _, err := FollowTagReference(&stableImageStream, targetTag)
+				if err != nil {
+					log.Printf("Not cross-tagging %s/%s:%s: %v", stableImageStream.Namespace, stableImageStream.Name, targetTag, err)
+					break
+				}
+				log.Printf("Cross-tagging %s:%s from %s/%s:%s", stableImageStream.Name, targetTag, stableImageStream.Namespace, stableImageStream.Name, resolvedTag)
 				var id string
 				for _, tagStatus := range stableImageStream.Status.Tags {
-					if tagStatus.Tag == targetTag {
+					if tagStatus.Tag == resolvedTag {
 						id = tagStatus.Items[0].Image
 					}
 				}
 				if len(id) == 0 {
-					return fmt.Errorf("no image found backing %s/%s:%s", stableImageStream.Namespace, stableImageStream.Name, targetTag)
+					return fmt.Errorf("no image found backing %s/%s:%s", stableImageStream.Namespace, stableImageStream.Name, resolvedTag)
 				}
 				ist := &imageapi.ImageStreamTag{
 					ObjectMeta: meta.ObjectMeta{
@@ -158,11 +180,10 @@ func (s *releaseImagesTagStep) Run(ctx context.Context, dry bool) error {
 				}
 
 				if dry {
-					istJSON, err := json.Marshal(ist)
-					if err != nil {
-						return fmt.Errorf("failed to marshal imagestreamtag: %v", err)
+					if err := s.sink.Add(ist); err != nil {
+						return err
 					}
-					fmt.Printf("%s\n", istJSON)
+					addedTag = true
 					continue
 				}
 				ist, err := s.istClient.ImageStreamTags(s.jobSpec.Namespace()).Create(ist)
@@ -175,6 +196,16 @@ func (s *releaseImagesTagStep) Run(ctx context.Context, dry bool) error {
 				}
 			}
 		}
+
+		// Flush once this stream's tags are all in the sink, before
+		// moving on to the next (differently-named) stable image
+		// stream, so per-stream dry-run sinks don't coalesce tags
+		// from unrelated streams together.
+		if dry && addedTag {
+			if err := s.sink.Flush(); err != nil {
+				return err
+			}
+		}
 	}
 
 	return nil
@@ -275,7 +306,19 @@ func (s *releaseImagesTagStep) Provides() (api.ParameterMap, api.StepLink) {
 
 func (s *releaseImagesTagStep) Name() string { return "" }
 
-func ReleaseImagesTagStep(config api.ReleaseTagConfiguration, istClient imageclientset.ImageStreamTagsGetter, isGetter imageclientset.ImageStreamsGetter, routeClient routeclientset.RoutesGetter, configMapClient coreclientset.ConfigMapsGetter, params *DeferredParameters, jobSpec *JobSpec) api.Step {
+// ReleaseImagesTagStep creates a step that tags a full release suite
+// of images into the job's namespace. sink, if nil, defaults to
+// printing each dry-run object to stdout as its own JSON document;
+// pass NewImageStreamImportDryRunSink to emit ImageStreamImports
+// instead. The sink is flushed once per source image stream, so the
+// output holds one ImageStreamImport per stream: with config.Name set
+// that is one import carrying every tag of the stable stream; with
+// config.Tag set each stream contributes one import with a single
+// Images[] entry.
+func ReleaseImagesTagStep(config api.ReleaseTagConfiguration, istClient imageclientset.ImageStreamTagsGetter, isGetter imageclientset.ImageStreamsGetter, routeClient routeclientset.RoutesGetter, configMapClient coreclientset.ConfigMapsGetter, params *DeferredParameters, jobSpec *JobSpec, sink DryRunSink) api.Step {
+	if sink == nil {
+		sink = NewStdoutDryRunSink()
+	}
 	return &releaseImagesTagStep{
 		config:          config,
 		istClient:       istClient,
@@ -284,6 +327,7 @@ func ReleaseImagesTagStep(config api.ReleaseTagConfiguration, istClient imagecli
 		configMapClient: configMapClient,
 		params:          params,
 		jobSpec:         jobSpec,
+		sink:            sink,
 	}
 }
 
@@ -0,0 +1,56 @@
+package steps
+
+import "sync"
+
+const (
+	// PipelineImageStream is the name of the ImageStream used to
+	// hold images produced and consumed by the steps of a single
+	// ci-operator job.
+	PipelineImageStream = "pipeline"
+	// StableImageStream is the name of the ImageStream that holds
+	// the release images tagged in by releaseImagesTagStep.
+	StableImageStream = "stable"
+	// RPMRepoName is the name of the Route that serves the RPM
+	// repository built by the job, if any.
+	RPMRepoName = "rpm-repo"
+)
+
+// JobSpec carries the identity of the job ci-operator is running
+// as, including the namespace it is allowed to create resources in.
+type JobSpec struct {
+	namespace string
+}
+
+// Namespace returns the namespace the job's resources should be
+// created in.
+func (s *JobSpec) Namespace() string {
+	return s.namespace
+}
+
+// DeferredParameters holds environment values that are not known
+// until some step of the job has executed, and lets later steps
+// read values set by earlier ones.
+type DeferredParameters struct {
+	lock   sync.RWMutex
+	values map[string]string
+}
+
+// NewDeferredParameters creates a new, empty set of parameters.
+func NewDeferredParameters() *DeferredParameters {
+	return &DeferredParameters{values: map[string]string{}}
+}
+
+// Set records the resolved value of a parameter.
+func (p *DeferredParameters) Set(name, value string) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.values[name] = value
+}
+
+// Get returns the resolved value of a parameter, if any.
+func (p *DeferredParameters) Get(name string) (string, bool) {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+	value, ok := p.values[name]
+	return value, ok
+}
@@ -0,0 +1,72 @@
+package steps
+
+import (
+	"fmt"
+	"strings"
+
+	imageapi "github.com/openshift/api/image/v1"
+)
+
+// maxTagReferenceDepth bounds how many alias hops FollowTagReference
+// will walk before giving up, so a misconfigured cycle of
+// ImageStreamTag references fails fast instead of looping forever.
+const maxTagReferenceDepth = 10
+
+// SplitImageStreamTag splits a "name:tag" string into its name and
+// tag components.
+func SplitImageStreamTag(nameAndTag string) (name, tag string, ok bool) {
+	parts := strings.SplitN(nameAndTag, ":", 2)
+	if len(parts) != 2 || len(parts[0]) == 0 || len(parts[1]) == 0 {
+		return "", "", false
+	}
+	return parts[0], parts[1], true
+}
+
+// FollowTagReference walks an ImageStream's tags starting at tag,
+// following any ImageStreamTag references (e.g. "latest" aliasing
+// "v4.2") until it reaches a tag whose From is not a reference to
+// another tag on the same stream. It returns the name of the tag
+// that terminates the chain along with its TagReference. A reference
+// to a tag in another namespace is reported as an error.
+func FollowTagReference(is *imageapi.ImageStream, tag string) (string, *imageapi.TagReference, error) {
+	seen := map[string]bool{}
+	for depth := 0; ; depth++ {
+		if depth > maxTagReferenceDepth {
+			return "", nil, fmt.Errorf("tag %q on image stream %s/%s exceeds the maximum alias depth of %d", tag, is.Namespace, is.Name, maxTagReferenceDepth)
+		}
+		if seen[tag] {
+			return "", nil, fmt.Errorf("tag %q on image stream %s/%s is part of a reference cycle", tag, is.Namespace, is.Name)
+		}
+		seen[tag] = true
+
+		tagRef := findSpecTag(is, tag)
+		if tagRef == nil {
+			return "", nil, fmt.Errorf("tag %q does not exist on image stream %s/%s", tag, is.Namespace, is.Name)
+		}
+		if tagRef.From == nil || tagRef.From.Kind != "ImageStreamTag" {
+			return tag, tagRef, nil
+		}
+
+		name, next, ok := SplitImageStreamTag(tagRef.From.Name)
+		if !ok {
+			// A bare tag name refers to a tag on the same stream.
+			name, next = is.Name, tagRef.From.Name
+		}
+		if len(tagRef.From.Namespace) > 0 && tagRef.From.Namespace != is.Namespace {
+			return "", nil, fmt.Errorf("tag %q on image stream %s/%s references a tag on another namespace (%s/%s), which is not supported", tag, is.Namespace, is.Name, tagRef.From.Namespace, name)
+		}
+		if name != is.Name {
+			return tag, tagRef, nil
+		}
+		tag = next
+	}
+}
+
+func findSpecTag(is *imageapi.ImageStream, tag string) *imageapi.TagReference {
+	for i := range is.Spec.Tags {
+		if is.Spec.Tags[i].Name == tag {
+			return &is.Spec.Tags[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+package steps
+
+import (
+	"testing"
+
+	imageapi "github.com/openshift/api/image/v1"
+	coreapi "k8s.io/api/core/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestSplitImageStreamTag(t *testing.T) {
+	if name, tag, ok := SplitImageStreamTag("foo:bar"); !ok || name != "foo" || tag != "bar" {
+		t.Errorf("got %q %q %v, expected foo bar true", name, tag, ok)
+	}
+	if _, _, ok := SplitImageStreamTag("foo"); ok {
+		t.Errorf("expected ok=false for a string with no tag")
+	}
+}
+
+func TestFollowTagReference(t *testing.T) {
+	is := &imageapi.ImageStream{
+		ObjectMeta: meta.ObjectMeta{Namespace: "ns", Name: "stable"},
+		Spec: imageapi.ImageStreamSpec{
+			Tags: []imageapi.TagReference{
+				{Name: "latest", From: &coreapi.ObjectReference{Kind: "ImageStreamTag", Name: "stable:v4.2"}},
+				{Name: "v4.2", From: &coreapi.ObjectReference{Kind: "DockerImage", Name: "quay.io/foo/bar@sha256:abc"}},
+				{Name: "loop-a", From: &coreapi.ObjectReference{Kind: "ImageStreamTag", Name: "stable:loop-b"}},
+				{Name: "loop-b", From: &coreapi.ObjectReference{Kind: "ImageStreamTag", Name: "stable:loop-a"}},
+			},
+		},
+	}
+
+	resolved, ref, err := FollowTagReference(is, "latest")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resolved != "v4.2" {
+		t.Errorf("expected to resolve to v4.2, got %s", resolved)
+	}
+	if ref.From.Name != "quay.io/foo/bar@sha256:abc" {
+		t.Errorf("unexpected resolved tag reference: %#v", ref)
+	}
+
+	if _, _, err := FollowTagReference(is, "loop-a"); err == nil {
+		t.Errorf("expected an error for a cyclic tag reference")
+	}
+
+	if _, _, err := FollowTagReference(is, "missing"); err == nil {
+		t.Errorf("expected an error for a nonexistent tag")
+	}
+}